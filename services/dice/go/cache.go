@@ -0,0 +1,311 @@
+package main
+
+import (
+	"container/list"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	invalidationChannel = "dice:invalidate"
+
+	defaultCacheSize = 10000
+	defaultCacheTTL  = 30 * time.Second
+)
+
+// GameStateStore is the abstraction RPC handlers depend on for reading and
+// writing game state, so the storage layer (Redis only, or an LRU fronting
+// Redis) can change without touching the handlers.
+type GameStateStore interface {
+	Get(ctx context.Context, username string) (*GameState, error)
+	Save(ctx context.Context, username string, state *GameState) error
+	Delete(ctx context.Context, username string) error
+}
+
+// cacheEntry is one LRU slot.
+type cacheEntry struct {
+	username string
+	state    *GameState
+	expires  time.Time
+}
+
+// lruCache is a small, bounded, TTL'd in-process cache: a doubly linked
+// list for recency plus a map for lookup, guarded by a single mutex.
+// Game-state traffic per replica doesn't warrant anything fancier.
+type lruCache struct {
+	mu    sync.Mutex
+	size  int
+	ttl   time.Duration
+	ll    *list.List
+	items map[string]*list.Element
+}
+
+func newLRUCache(size int, ttl time.Duration) *lruCache {
+	return &lruCache{
+		size:  size,
+		ttl:   ttl,
+		ll:    list.New(),
+		items: make(map[string]*list.Element),
+	}
+}
+
+// cloneGameState returns a deep copy of state. Callers that read a cached
+// entry (e.g. Roll, which mutates Phase/Point/ComeBets in place before
+// saving) must get their own copy rather than the pointer stored in the
+// LRU - otherwise two concurrent rolls for the same key race on the same
+// struct, and ComeBets in particular can tear under concurrent append.
+func cloneGameState(state *GameState) *GameState {
+	if state == nil {
+		return nil
+	}
+	clone := *state
+	if state.ComeBets != nil {
+		clone.ComeBets = append([]ComeBet(nil), state.ComeBets...)
+	}
+	return &clone
+}
+
+// get returns a copy of the cached state if present and not expired.
+func (c *lruCache) get(username string) (*GameState, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[username]
+	if !ok {
+		return nil, false
+	}
+	c.ll.MoveToFront(el)
+
+	entry := el.Value.(*cacheEntry)
+	if time.Now().After(entry.expires) {
+		return nil, false
+	}
+	return cloneGameState(entry.state), true
+}
+
+// getStale returns a copy of the cached state even if its TTL has passed,
+// without evicting it. Used as a fallback when Redis is down. Staleness has
+// no time limit: an expired entry is served until it is pushed out of the
+// LRU by size, evicted by an invalidation message, or replaced by a write.
+func (c *lruCache) getStale(username string) (*GameState, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[username]
+	if !ok {
+		return nil, false
+	}
+	return cloneGameState(el.Value.(*cacheEntry).state), true
+}
+
+// set stores a copy of state so the cache's entry is never aliased with a
+// pointer the caller (or a future cloneGameState-less caller) keeps mutating.
+func (c *lruCache) set(username string, state *GameState) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	state = cloneGameState(state)
+
+	if el, ok := c.items[username]; ok {
+		entry := el.Value.(*cacheEntry)
+		entry.state = state
+		entry.expires = time.Now().Add(c.ttl)
+		c.ll.MoveToFront(el)
+		return
+	}
+
+	entry := &cacheEntry{username: username, state: state, expires: time.Now().Add(c.ttl)}
+	c.items[username] = c.ll.PushFront(entry)
+
+	if c.ll.Len() > c.size {
+		oldest := c.ll.Back()
+		if oldest != nil {
+			c.ll.Remove(oldest)
+			delete(c.items, oldest.Value.(*cacheEntry).username)
+		}
+	}
+}
+
+func (c *lruCache) evict(username string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[username]; ok {
+		c.ll.Remove(el)
+		delete(c.items, username)
+	}
+}
+
+// cacheHits and cacheMisses back the cache.hit_ratio span attribute. They
+// are process-wide counters rather than per-request since a single lookup
+// only ever produces one hit or one miss.
+var (
+	cacheHits   uint64
+	cacheMisses uint64
+)
+
+func recordCacheResult(ctx context.Context, hit bool) {
+	if hit {
+		atomic.AddUint64(&cacheHits, 1)
+	} else {
+		atomic.AddUint64(&cacheMisses, 1)
+	}
+	hits := atomic.LoadUint64(&cacheHits)
+	misses := atomic.LoadUint64(&cacheMisses)
+	ratio := 0.0
+	if hits+misses > 0 {
+		ratio = float64(hits) / float64(hits+misses)
+	}
+
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(
+		attribute.Bool("cache.hit", hit),
+		attribute.Float64("cache.hit_ratio", ratio),
+	)
+}
+
+// layeredGameStateStore fronts Redis with a bounded, TTL'd LRU. Saves and
+// deletes go through to Redis first and then update the LRU; both publish
+// an invalidation message so other vegas-dice-service replicas evict their
+// own copy of the same key. Messages are tagged with this replica's own
+// instanceID so its own subscriber - Redis fans PUBLISH out to every
+// subscriber including the publisher - can ignore them instead of evicting
+// the entry it just populated.
+type layeredGameStateStore struct {
+	lru        *lruCache
+	instanceID string
+}
+
+// NewGameStateStore builds the layered store and starts its invalidation
+// subscriber. Size and TTL are configurable via DICE_CACHE_SIZE and
+// DICE_CACHE_TTL_SECONDS so they can be tuned per deployment.
+func NewGameStateStore() GameStateStore {
+	size := defaultCacheSize
+	if v := os.Getenv("DICE_CACHE_SIZE"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			size = n
+		}
+	}
+	ttl := defaultCacheTTL
+	if v := os.Getenv("DICE_CACHE_TTL_SECONDS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			ttl = time.Duration(n) * time.Second
+		}
+	}
+
+	store := &layeredGameStateStore{lru: newLRUCache(size, ttl), instanceID: newInstanceID()}
+	store.subscribeInvalidations()
+	return store
+}
+
+// newInstanceID returns a short random identifier unique to this process,
+// used to tell this replica's own invalidation messages apart from ones
+// published by other replicas.
+func newInstanceID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		// Extremely unlikely; fall back to a fixed marker rather than a
+		// duplicate-prone empty string.
+		return "unknown-instance"
+	}
+	return hex.EncodeToString(b)
+}
+
+func (s *layeredGameStateStore) Get(ctx context.Context, username string) (*GameState, error) {
+	tracer := otel.Tracer("vegas-dice-service")
+	ctx, span := tracer.Start(ctx, "cache_get_game_state")
+	defer span.End()
+
+	if state, ok := s.lru.get(username); ok {
+		recordCacheResult(ctx, true)
+		return state, nil
+	}
+	recordCacheResult(ctx, false)
+
+	state, err := GetGameState(ctx, username)
+	if err != nil {
+		// Redis is unreachable: serve a stale LRU entry, however old, rather
+		// than failing the roll outright.
+		if stale, ok := s.lru.getStale(username); ok {
+			log.Printf("Warning: serving stale cached game state for %s: %v", username, err)
+			return stale, nil
+		}
+		return nil, err
+	}
+	if state != nil {
+		s.lru.set(username, state)
+	}
+	return state, nil
+}
+
+func (s *layeredGameStateStore) Save(ctx context.Context, username string, state *GameState) error {
+	if err := SaveGameState(ctx, username, state); err != nil {
+		return err
+	}
+	s.lru.set(username, state)
+	s.publishInvalidation(ctx, username)
+	return nil
+}
+
+func (s *layeredGameStateStore) Delete(ctx context.Context, username string) error {
+	if err := DeleteGameState(ctx, username); err != nil {
+		return err
+	}
+	s.lru.evict(username)
+	s.publishInvalidation(ctx, username)
+	return nil
+}
+
+// invalidationMessage formats/parses the "<instanceID>|<username>" payload
+// published on invalidationChannel.
+func invalidationMessage(instanceID, username string) string {
+	return instanceID + "|" + username
+}
+
+func parseInvalidationMessage(payload string) (instanceID, username string, ok bool) {
+	instanceID, username, ok = strings.Cut(payload, "|")
+	return instanceID, username, ok
+}
+
+// publishInvalidation tells every other replica to evict its copy of
+// username's game state. The message is tagged with this replica's own
+// instanceID so its own subscriber can ignore it below.
+func (s *layeredGameStateStore) publishInvalidation(ctx context.Context, username string) {
+	if !redisHealthy.Load() {
+		return
+	}
+	msg := invalidationMessage(s.instanceID, username)
+	if err := redisClient.Publish(ctx, invalidationChannel, msg).Err(); err != nil {
+		log.Printf("Warning: Failed to publish cache invalidation for %s: %v", username, err)
+	}
+}
+
+// subscribeInvalidations listens for invalidation messages published by
+// other replicas and evicts the matching LRU entry. Messages tagged with
+// this replica's own instanceID are skipped - Redis PUBLISH fans out to the
+// publisher's own subscription too, and without this check a replica would
+// immediately evict every entry it just wrote.
+func (s *layeredGameStateStore) subscribeInvalidations() {
+	sub := redisClient.Subscribe(context.Background(), invalidationChannel)
+	go func() {
+		for msg := range sub.Channel() {
+			instanceID, username, ok := parseInvalidationMessage(msg.Payload)
+			if !ok || instanceID == s.instanceID {
+				continue
+			}
+			s.lru.evict(username)
+		}
+	}()
+}
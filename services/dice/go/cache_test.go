@@ -0,0 +1,98 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLRUCacheSetGet(t *testing.T) {
+	c := newLRUCache(10, time.Minute)
+
+	if _, ok := c.get("alice"); ok {
+		t.Fatal("expected miss on empty cache")
+	}
+
+	state := &GameState{BetType: "pass", Sum: 7}
+	c.set("alice", state)
+
+	got, ok := c.get("alice")
+	if !ok {
+		t.Fatal("expected hit after set")
+	}
+	if got == state {
+		t.Fatal("expected get to return a copy, not the same pointer passed to set")
+	}
+	if got.BetType != state.BetType || got.Sum != state.Sum {
+		t.Fatalf("got %+v, want equivalent of %+v", got, state)
+	}
+
+	// Mutating the returned copy must not corrupt the cached entry - this is
+	// what protects concurrent rolls for the same key from racing on one
+	// shared *GameState.
+	got.ComeBets = append(got.ComeBets, ComeBet{Point: 6, Amount: 5})
+	again, _ := c.get("alice")
+	if len(again.ComeBets) != 0 {
+		t.Fatalf("mutating a returned copy leaked into the cache: %+v", again)
+	}
+}
+
+func TestLRUCacheExpiry(t *testing.T) {
+	c := newLRUCache(10, time.Millisecond)
+	c.set("alice", &GameState{Sum: 7})
+
+	time.Sleep(5 * time.Millisecond)
+
+	if _, ok := c.get("alice"); ok {
+		t.Fatal("expected miss after TTL expiry")
+	}
+	if _, ok := c.getStale("alice"); !ok {
+		t.Fatal("expected getStale to still return the expired entry")
+	}
+}
+
+func TestLRUCacheEvictsOldest(t *testing.T) {
+	c := newLRUCache(2, time.Minute)
+	c.set("alice", &GameState{Sum: 2})
+	c.set("bob", &GameState{Sum: 3})
+	c.set("carol", &GameState{Sum: 4})
+
+	if _, ok := c.get("alice"); ok {
+		t.Fatal("expected oldest entry to be evicted once over size")
+	}
+	if _, ok := c.get("bob"); !ok {
+		t.Fatal("expected bob to still be cached")
+	}
+	if _, ok := c.get("carol"); !ok {
+		t.Fatal("expected carol to still be cached")
+	}
+}
+
+func TestLRUCacheEvict(t *testing.T) {
+	c := newLRUCache(10, time.Minute)
+	c.set("alice", &GameState{Sum: 7})
+	c.evict("alice")
+
+	if _, ok := c.get("alice"); ok {
+		t.Fatal("expected miss after evict")
+	}
+}
+
+// TestInvalidationMessageSelfOrigin guards the bug where a replica evicted
+// the entry it had just cached because Redis PUBLISH fans out to the
+// publisher's own subscription: a parsed message tagged with this
+// instance's own ID must be recognized as self-originated so the
+// subscriber can skip it instead of evicting.
+func TestInvalidationMessageSelfOrigin(t *testing.T) {
+	msg := invalidationMessage("instance-a", "alice")
+
+	instanceID, username, ok := parseInvalidationMessage(msg)
+	if !ok {
+		t.Fatal("expected message to parse")
+	}
+	if username != "alice" {
+		t.Fatalf("got username %q, want alice", username)
+	}
+	if instanceID != "instance-a" {
+		t.Fatalf("got instanceID %q, want instance-a", instanceID)
+	}
+}
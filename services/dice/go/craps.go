@@ -0,0 +1,300 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Craps phases. The table starts in come_out; a pass/don't-pass bet that
+// doesn't resolve immediately establishes a Point and moves the table into
+// point phase until that point (or a seven) repeats.
+const (
+	phaseComeOut = "come_out"
+	phasePoint   = "point"
+)
+
+// buyCommissionRate is the standard 5% vig charged on Buy and Lay bets,
+// taken out of the payout on a win.
+const buyCommissionRate = 0.05
+
+// ComeBet is one Come or Don't Come bet that has traveled to its own point.
+// Unlike the main pass-line bet, several of these can be in flight at once,
+// each resolving independently against later rolls.
+type ComeBet struct {
+	Point    int     `json:"point"`
+	Amount   float64 `json:"amount"`
+	DontCome bool    `json:"dont_come"`
+}
+
+// ComeBetResolution is a traveling Come/Don't Come bet that settled against
+// the current roll, returned to the caller alongside the primary bet's
+// resolution.
+type ComeBetResolution struct {
+	ComeBet
+	Win    bool    `json:"win"`
+	Payout float64 `json:"payout"`
+}
+
+// CrapsResolution is the outcome of resolving one bet against one roll.
+// Resolved is false when the bet is still working - e.g. the roll just
+// established a point - so the caller knows not to treat Payout as final.
+type CrapsResolution struct {
+	Win        bool
+	Payout     float64
+	Multiplier float64
+	Resolved   bool
+	Detail     string
+}
+
+// resolveCrapsRoll resolves betType against sum given the table's current
+// Phase/Point, mutating state in place for any phase transition or newly
+// traveled Come/Don't Come bet.
+func resolveCrapsRoll(state *GameState, betType string, betAmount float64, d1, d2, sum int) CrapsResolution {
+	switch betType {
+	case "pass":
+		return resolvePassLine(state, betAmount, sum, false)
+	case "dont_pass":
+		return resolvePassLine(state, betAmount, sum, true)
+	case "come":
+		return resolveComeBet(state, betAmount, sum, false)
+	case "dont_come":
+		return resolveComeBet(state, betAmount, sum, true)
+	case "field":
+		win := sum == 2 || sum == 3 || sum == 4 || sum == 9 || sum == 10 || sum == 11 || sum == 12
+		return oneRollResolution(win, betAmount, 2, "field")
+	case "snake_eyes":
+		return oneRollResolution(d1 == 1 && d2 == 1, betAmount, 30, "snake eyes")
+	case "boxcars":
+		return oneRollResolution(d1 == 6 && d2 == 6, betAmount, 30, "boxcars")
+	case "seven_out":
+		return oneRollResolution(sum == 7, betAmount, 4, "seven")
+	default:
+		if point, ok := parsePlaceNumber(betType); ok {
+			return resolvePlaceBet(point, isBuyBet(betType), betAmount, sum)
+		}
+		if point, ok := parseLayNumber(betType); ok {
+			return resolveLayBet(point, betAmount, sum)
+		}
+		return resolvePassLine(state, betAmount, sum, false)
+	}
+}
+
+// oneRollResolution resolves a bet that always settles on the roll it was
+// made on, independent of the come-out/point state machine.
+func oneRollResolution(win bool, betAmount float64, multiplier float64, detail string) CrapsResolution {
+	payout := 0.0
+	if win {
+		payout = betAmount * multiplier
+	}
+	return CrapsResolution{Win: win, Payout: payout, Multiplier: multiplier, Resolved: true, Detail: detail}
+}
+
+// evenMoneyPayout pays 2x the bet (stake + even-money win) on a win, 0 on a
+// loss, and returns the stake on a push.
+func evenMoneyPayout(betAmount float64, win, push bool) float64 {
+	switch {
+	case push:
+		return betAmount
+	case win:
+		return betAmount * 2
+	default:
+		return 0
+	}
+}
+
+// resolvePassLine implements the real craps pass-line state machine: on
+// come_out, 7/11 wins and 2/3/12 loses outright while any other total becomes
+// the Point and moves the table to point phase; in point phase, repeating
+// the Point wins and a seven-out loses and returns the table to come_out.
+// dontPass mirrors the logic with 12 barred (a push rather than a win).
+func resolvePassLine(state *GameState, betAmount float64, sum int, dontPass bool) CrapsResolution {
+	if state.Phase == phasePoint {
+		switch sum {
+		case state.Point:
+			state.Phase = phaseComeOut
+			state.Point = 0
+			return CrapsResolution{Win: !dontPass, Payout: evenMoneyPayout(betAmount, !dontPass, false), Multiplier: 2, Resolved: true, Detail: "point made"}
+		case 7:
+			state.Phase = phaseComeOut
+			state.Point = 0
+			return CrapsResolution{Win: dontPass, Payout: evenMoneyPayout(betAmount, dontPass, false), Multiplier: 2, Resolved: true, Detail: "seven-out"}
+		default:
+			return CrapsResolution{Resolved: false, Detail: fmt.Sprintf("point phase continues (point %d)", state.Point)}
+		}
+	}
+
+	switch sum {
+	case 7, 11:
+		return CrapsResolution{Win: !dontPass, Payout: evenMoneyPayout(betAmount, !dontPass, false), Multiplier: 2, Resolved: true, Detail: "natural"}
+	case 2, 3:
+		return CrapsResolution{Win: dontPass, Payout: evenMoneyPayout(betAmount, dontPass, false), Multiplier: 2, Resolved: true, Detail: "craps"}
+	case 12:
+		if dontPass {
+			// 12 is barred on don't-pass: neither side wins, stake returns.
+			return CrapsResolution{Win: false, Payout: betAmount, Multiplier: 1, Resolved: true, Detail: "push (bar 12)"}
+		}
+		return CrapsResolution{Win: false, Payout: 0, Multiplier: 2, Resolved: true, Detail: "craps"}
+	default:
+		state.Phase = phasePoint
+		state.Point = sum
+		return CrapsResolution{Resolved: false, Detail: fmt.Sprintf("point established: %d", sum)}
+	}
+}
+
+// resolveComeBet places a fresh Come/Don't Come bet and resolves it against
+// the current roll exactly like a pass-line come-out: naturals and craps
+// settle immediately, anything else travels to its own point and is
+// appended to state.ComeBets so later rolls can resolve it in
+// resolveTravelingComeBets.
+func resolveComeBet(state *GameState, betAmount float64, sum int, dontCome bool) CrapsResolution {
+	switch sum {
+	case 7, 11:
+		return CrapsResolution{Win: !dontCome, Payout: evenMoneyPayout(betAmount, !dontCome, false), Multiplier: 2, Resolved: true, Detail: "come natural"}
+	case 2, 3:
+		return CrapsResolution{Win: dontCome, Payout: evenMoneyPayout(betAmount, dontCome, false), Multiplier: 2, Resolved: true, Detail: "come craps"}
+	case 12:
+		if dontCome {
+			return CrapsResolution{Win: false, Payout: betAmount, Multiplier: 1, Resolved: true, Detail: "come push (bar 12)"}
+		}
+		return CrapsResolution{Win: false, Payout: 0, Multiplier: 2, Resolved: true, Detail: "come craps"}
+	default:
+		state.ComeBets = append(state.ComeBets, ComeBet{Point: sum, Amount: betAmount, DontCome: dontCome})
+		return CrapsResolution{Resolved: false, Detail: fmt.Sprintf("come point established: %d", sum)}
+	}
+}
+
+// resolveTravelingComeBets settles every Come/Don't Come bet already
+// traveled to its own point against this roll. A seven resolves all of them
+// at once (wins for Don't Come, losses for Come); otherwise a repeat of a
+// bet's own point resolves just that one. state.ComeBets is updated in
+// place to only the bets still in flight.
+func resolveTravelingComeBets(state *GameState, sum int) []ComeBetResolution {
+	if len(state.ComeBets) == 0 {
+		return nil
+	}
+
+	if sum == 7 {
+		settled := make([]ComeBetResolution, 0, len(state.ComeBets))
+		for _, cb := range state.ComeBets {
+			settled = append(settled, ComeBetResolution{ComeBet: cb, Win: cb.DontCome, Payout: evenMoneyPayout(cb.Amount, cb.DontCome, false)})
+		}
+		state.ComeBets = nil
+		return settled
+	}
+
+	var settled []ComeBetResolution
+	remaining := state.ComeBets[:0]
+	for _, cb := range state.ComeBets {
+		if cb.Point == sum {
+			settled = append(settled, ComeBetResolution{ComeBet: cb, Win: !cb.DontCome, Payout: evenMoneyPayout(cb.Amount, !cb.DontCome, false)})
+			continue
+		}
+		remaining = append(remaining, cb)
+	}
+	state.ComeBets = remaining
+	return settled
+}
+
+// placeOdds returns the standard place-bet payout ratio for number point.
+func placeOdds(point int) (num, den int) {
+	switch point {
+	case 4, 10:
+		return 9, 5
+	case 5, 9:
+		return 7, 5
+	default: // 6, 8
+		return 7, 6
+	}
+}
+
+// buyOdds returns the true-odds payout ratio a Buy bet pays before the 5%
+// commission is taken out.
+func buyOdds(point int) (num, den int) {
+	switch point {
+	case 4, 10:
+		return 2, 1
+	case 5, 9:
+		return 3, 2
+	default: // 6, 8
+		return 6, 5
+	}
+}
+
+// resolvePlaceBet resolves a Place (standard payout) or Buy (true odds,
+// minus 5% commission) bet on point: it wins when point repeats, loses on a
+// seven, and keeps working on anything else.
+func resolvePlaceBet(point int, buy bool, betAmount float64, sum int) CrapsResolution {
+	num, den := placeOdds(point)
+	if buy {
+		num, den = buyOdds(point)
+	}
+
+	switch sum {
+	case point:
+		payout := betAmount * float64(num) / float64(den)
+		if buy {
+			payout -= betAmount * buyCommissionRate
+		}
+		return CrapsResolution{Win: true, Payout: payout, Multiplier: float64(num) / float64(den), Resolved: true, Detail: fmt.Sprintf("place %d hit", point)}
+	case 7:
+		return CrapsResolution{Win: false, Payout: 0, Resolved: true, Detail: "seven-out"}
+	default:
+		return CrapsResolution{Resolved: false, Detail: fmt.Sprintf("place %d still working", point)}
+	}
+}
+
+// resolveLayBet resolves a Lay bet: the mirror of a Buy bet, betting that
+// seven comes before point. It wins on a seven (true odds minus 5%
+// commission) and loses if point repeats first.
+func resolveLayBet(point int, betAmount float64, sum int) CrapsResolution {
+	num, den := buyOdds(point)
+
+	switch sum {
+	case 7:
+		payout := betAmount * float64(den) / float64(num)
+		payout -= payout * buyCommissionRate
+		return CrapsResolution{Win: true, Payout: payout, Multiplier: float64(den) / float64(num), Resolved: true, Detail: fmt.Sprintf("lay %d: seven-out", point)}
+	case point:
+		return CrapsResolution{Win: false, Payout: 0, Resolved: true, Detail: fmt.Sprintf("lay %d: point hit", point)}
+	default:
+		return CrapsResolution{Resolved: false, Detail: fmt.Sprintf("lay %d still working", point)}
+	}
+}
+
+// parsePlaceNumber extracts the point number from a "place_N" or "buy_N"
+// bet type, validating it's one of the numbers craps lets you place.
+func parsePlaceNumber(betType string) (int, bool) {
+	if !strings.HasPrefix(betType, "place_") && !strings.HasPrefix(betType, "buy_") {
+		return 0, false
+	}
+	return parsePlaceableNumber(betType)
+}
+
+// parseLayNumber extracts the point number from a "lay_N" bet type.
+func parseLayNumber(betType string) (int, bool) {
+	if !strings.HasPrefix(betType, "lay_") {
+		return 0, false
+	}
+	return parsePlaceableNumber(betType)
+}
+
+func isBuyBet(betType string) bool {
+	return strings.HasPrefix(betType, "buy_")
+}
+
+func parsePlaceableNumber(betType string) (int, bool) {
+	parts := strings.SplitN(betType, "_", 2)
+	if len(parts) != 2 {
+		return 0, false
+	}
+	n, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, false
+	}
+	switch n {
+	case 4, 5, 6, 8, 9, 10:
+		return n, true
+	}
+	return 0, false
+}
@@ -0,0 +1,134 @@
+package main
+
+import "testing"
+
+func TestResolvePassLineEstablishesAndMakesPoint(t *testing.T) {
+	state := &GameState{Phase: phaseComeOut}
+
+	res := resolvePassLine(state, 10, 6, false)
+	if res.Resolved {
+		t.Fatal("expected establishing a point to leave the bet unresolved")
+	}
+	if state.Phase != phasePoint || state.Point != 6 {
+		t.Fatalf("got phase %q point %d, want point phase 6", state.Phase, state.Point)
+	}
+
+	res = resolvePassLine(state, 10, 6, false)
+	if !res.Resolved || !res.Win || res.Payout != 20 {
+		t.Fatalf("got %+v, want a 20-unit win on point made", res)
+	}
+	if state.Phase != phaseComeOut || state.Point != 0 {
+		t.Fatalf("expected point made to return table to come_out, got phase %q point %d", state.Phase, state.Point)
+	}
+}
+
+func TestResolvePassLineSevenOut(t *testing.T) {
+	state := &GameState{Phase: phasePoint, Point: 6}
+
+	res := resolvePassLine(state, 10, 7, false)
+	if !res.Resolved || res.Win || res.Payout != 0 {
+		t.Fatalf("got %+v, want a loss on seven-out", res)
+	}
+	if state.Phase != phaseComeOut || state.Point != 0 {
+		t.Fatalf("expected seven-out to return table to come_out, got phase %q point %d", state.Phase, state.Point)
+	}
+}
+
+func TestResolveComeBetTravelsThenSettles(t *testing.T) {
+	state := &GameState{Phase: phasePoint, Point: 6}
+
+	res := resolveComeBet(state, 10, 5, false)
+	if res.Resolved {
+		t.Fatal("expected a come bet on 5 to travel instead of resolving immediately")
+	}
+	if len(state.ComeBets) != 1 || state.ComeBets[0].Point != 5 {
+		t.Fatalf("got ComeBets %+v, want one bet traveling to 5", state.ComeBets)
+	}
+
+	settled := resolveTravelingComeBets(state, 5)
+	if len(settled) != 1 || !settled[0].Win || settled[0].Payout != 20 {
+		t.Fatalf("got %+v, want a 20-unit win when the come point repeats", settled)
+	}
+	if len(state.ComeBets) != 0 {
+		t.Fatalf("expected the settled come bet to be removed, got %+v", state.ComeBets)
+	}
+}
+
+func TestResolveTravelingComeBetsSevenClearsAll(t *testing.T) {
+	state := &GameState{
+		ComeBets: []ComeBet{
+			{Point: 5, Amount: 10},
+			{Point: 9, Amount: 10, DontCome: true},
+		},
+	}
+
+	settled := resolveTravelingComeBets(state, 7)
+	if len(settled) != 2 {
+		t.Fatalf("got %d resolutions, want 2", len(settled))
+	}
+	for _, s := range settled {
+		if s.DontCome != s.Win {
+			t.Fatalf("got %+v, want a seven-out to win Don't Come bets and lose Come bets", s)
+		}
+	}
+	if state.ComeBets != nil {
+		t.Fatalf("expected seven-out to clear every traveling come bet, got %+v", state.ComeBets)
+	}
+}
+
+func TestResolveTravelingComeBetsOnlySettlesMatchingPoint(t *testing.T) {
+	state := &GameState{
+		ComeBets: []ComeBet{
+			{Point: 5, Amount: 10},
+			{Point: 9, Amount: 10},
+		},
+	}
+
+	settled := resolveTravelingComeBets(state, 5)
+	if len(settled) != 1 || settled[0].Point != 5 {
+		t.Fatalf("got %+v, want only the bet on 5 to settle", settled)
+	}
+	if len(state.ComeBets) != 1 || state.ComeBets[0].Point != 9 {
+		t.Fatalf("got %+v, want the bet on 9 left in flight", state.ComeBets)
+	}
+}
+
+func TestResolvePlaceBetPaysStandardOdds(t *testing.T) {
+	res := resolvePlaceBet(6, false, 30, 6)
+	if !res.Resolved || !res.Win || res.Payout != 35 {
+		t.Fatalf("got %+v, want a 35-unit win (7:6) on place 6 hit", res)
+	}
+}
+
+func TestResolvePlaceBetBuyChargesCommission(t *testing.T) {
+	res := resolvePlaceBet(4, true, 20, 4)
+	want := 20*2.0 - 20*buyCommissionRate
+	if !res.Resolved || !res.Win || res.Payout != want {
+		t.Fatalf("got payout %.4f, want %.4f (true odds minus 5%% vig)", res.Payout, want)
+	}
+}
+
+func TestResolveLayBetWinsOnSeven(t *testing.T) {
+	res := resolveLayBet(4, 40, 7)
+	if !res.Resolved || !res.Win {
+		t.Fatalf("got %+v, want a lay bet to win on seven-out", res)
+	}
+}
+
+func TestParsePlaceAndLayNumbers(t *testing.T) {
+	if n, ok := parsePlaceNumber("place_8"); !ok || n != 8 {
+		t.Fatalf("got (%d, %v), want (8, true)", n, ok)
+	}
+	if n, ok := parsePlaceNumber("buy_4"); !ok || n != 4 {
+		t.Fatalf("got (%d, %v), want (4, true)", n, ok)
+	}
+	if !isBuyBet("buy_4") || isBuyBet("place_4") {
+		t.Fatal("isBuyBet should only recognize the buy_ prefix")
+	}
+	if n, ok := parseLayNumber("lay_10"); !ok || n != 10 {
+		t.Fatalf("got (%d, %v), want (10, true)", n, ok)
+	}
+	if _, ok := parsePlaceNumber("place_7"); ok {
+		t.Fatal("7 is not a placeable number")
+	}
+}
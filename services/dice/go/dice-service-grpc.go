@@ -9,7 +9,10 @@ import (
 	"net"
 	"net/http"
 	"os"
+	"os/signal"
 	"strconv"
+	"sync/atomic"
+	"syscall"
 	"time"
 
 	"go.opentelemetry.io/otel"
@@ -18,6 +21,7 @@ import (
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/reflection"
 
+	"vegas-dice-service/fairness"
 	pb "vegas-dice-service/proto"
 )
 
@@ -46,6 +50,8 @@ func (m metadataTextMapCarrier) Keys() []string {
 
 type diceServer struct {
 	pb.UnimplementedDiceServiceServer
+
+	store GameStateStore
 }
 
 func (s *diceServer) Health(ctx context.Context, req *pb.HealthRequest) (*pb.HealthResponse, error) {
@@ -54,22 +60,44 @@ func (s *diceServer) Health(ctx context.Context, req *pb.HealthRequest) (*pb.Hea
 		serviceName = "vegas-dice-service"
 	}
 
+	status := "ok"
+	if !redisHealthy.Load() {
+		status = "degraded"
+	}
+
 	metadata := map[string]string{
-		"version":    "2.1.0",
-		"gameType":   "craps-dice",
-		"complexity": "medium",
-		"rtp":        "98.6%",
-		"owner":      "Dice-Games-Team",
-		"technology": "Go-Dice-gRPC",
+		"version":      "2.1.0",
+		"gameType":     "craps-dice",
+		"complexity":   "medium",
+		"rtp":          "98.6%",
+		"owner":        "Dice-Games-Team",
+		"technology":   "Go-Dice-gRPC",
+		"redisHealthy": strconv.FormatBool(redisHealthy.Load()),
 	}
 
 	return &pb.HealthResponse{
-		Status:   "ok",
+		Status:   status,
 		Service:  serviceName,
 		Metadata: metadata,
 	}, nil
 }
 
+// resolveStateKey returns the key multi-roll game state and fairness
+// sessions are stored under: the caller's session id when one was sent,
+// falling back to their username so un-sessioned callers keep the
+// single-table behavior this service had before sessions existed. Game
+// state and fairness sessions must use the same key - otherwise two
+// callers sharing a username (e.g. both "Anonymous") but sending different
+// session ids would collide on one seed chain, and one of them calling
+// RevealSeed would silently re-enable house-advantage for the other.
+// Callers that send no session id and share a username still share one key.
+func resolveStateKey(username, sessionID string) string {
+	if sessionID != "" {
+		return sessionID
+	}
+	return username
+}
+
 func (s *diceServer) Roll(ctx context.Context, req *pb.RollRequest) (*pb.RollResponse, error) {
 	tracer := otel.Tracer("vegas-dice-service")
 	ctx, span := tracer.Start(ctx, "dice_roll")
@@ -112,47 +140,107 @@ func (s *diceServer) Roll(ctx context.Context, req *pb.RollRequest) (*pb.RollRes
 		return nil, fmt.Errorf("come bets are disabled")
 	}
 
-	// Roll dice - generate random values (1-6 for each die)
-	// Note: rand.Seed should be called in main() for proper randomization
-	d1 := rand.Intn(6) + 1
-	d2 := rand.Intn(6) + 1
+	// Get username from request (if available in player_info)
+	username := "Anonymous"
+	if req.PlayerInfo != nil {
+		if u, ok := req.PlayerInfo["username"]; ok {
+			username = u
+		}
+	}
+
+	// Multi-roll bets (the point, and any traveling Come/Don't Come bets)
+	// persist across calls keyed by SessionId so a player can have more than
+	// one table going; un-sessioned callers fall back to their username,
+	// matching the single-table behavior this service had before sessions.
+	// The player's fairness session is keyed the same way below, so two
+	// anonymous callers that send different SessionIds never collapse onto
+	// one seed chain just because neither sent player_info.
+	stateKey := resolveStateKey(username, req.SessionId)
+
+	state, err := s.store.Get(ctx, stateKey)
+	if err != nil {
+		log.Printf("Warning: Failed to load game state for %s: %v", stateKey, err)
+	}
+	if state == nil {
+		state = &GameState{}
+	}
+	if state.Phase == "" {
+		state.Phase = phaseComeOut
+	}
+
+	// Use the player's provably-fair session if one was started via
+	// StartSession; otherwise fall back to math/rand for un-sessioned
+	// clients. House advantage must never run against a committed,
+	// verifiable seed chain, so it is gated out of fair mode below.
+	//
+	// GetFairnessSession can fail because Redis is down rather than because
+	// no session exists (it refuses to look up sessions while unhealthy), so
+	// that error must not be read as "this player never started a fair
+	// session": fairnessUnknown tracks that distinction and keeps house
+	// advantage disabled for the roll even though fairMode itself is false,
+	// so a Redis blip can't silently re-enable the house edge mid-session.
+	var rollSource fairness.RollSource = fairness.MathRandSource{}
+	fairSession, fairErr := GetFairnessSession(ctx, stateKey)
+	fairnessUnknown := fairErr != nil
+	if fairnessUnknown {
+		log.Printf("Warning: Failed to load fairness session for %s, rolling in unverifiable mode: %v", stateKey, fairErr)
+	}
+	fairMode := fairSession != nil && !fairSession.Revealed
+	// rollNonce is the per-roll nonce actually consumed from the HMAC chain,
+	// returned to the client so it can recompute and verify this exact roll
+	// against the session's seed chain once RevealSeed is called.
+	var rollNonce int64
+	if fairMode {
+		rollNonce = fairSession.Nonce
+		rollSource = fairSession.Source()
+	}
+	span.SetAttributes(
+		attribute.Bool("game.fair_mode", fairMode),
+		attribute.Bool("game.fairness_unknown", fairnessUnknown),
+	)
+
+	dice := fairness.RollDice(rollSource, 2)
+	d1, d2 := dice[0], dice[1]
 	sum := d1 + d2
 
-	// Determine win condition
-	var win bool
-	var payoutMultiplier float64
-
-	switch betType {
-	case "pass":
-		win = sum == 7 || sum == 11
-		payoutMultiplier = 2
-	case "dont_pass":
-		win = sum == 2 || sum == 3
-		payoutMultiplier = 2
-	case "field":
-		win = sum == 2 || sum == 3 || sum == 4 || sum == 9 || sum == 10 || sum == 11 || sum == 12
-		payoutMultiplier = 2
-	case "snake_eyes":
-		win = d1 == 1 && d2 == 1
-		payoutMultiplier = 30
-	case "boxcars":
-		win = d1 == 6 && d2 == 6
-		payoutMultiplier = 30
-	case "seven_out":
-		win = sum == 7
-		payoutMultiplier = 4
-	default:
-		win = sum == 7 || sum == 11
-		payoutMultiplier = 2
-	}
-
-	payout := 0.0
-	if win {
-		payout = betAmount * payoutMultiplier
+	if fairMode {
+		if err := SaveFairnessSession(ctx, stateKey, fairSession); err != nil {
+			log.Printf("Warning: Failed to persist fairness session for %s: %v", stateKey, err)
+		}
+	}
 
+	// Settle any Come/Don't Come bets already traveling to their own point
+	// before resolving this roll's primary bet - they resolve off every
+	// roll, not just ones placing a new come bet. Their payouts are real
+	// money owed to the player, so they're folded into this roll's total
+	// payout rather than only logged.
+	traveled := resolveTravelingComeBets(state, sum)
+	comeBetPayout := 0.0
+	for _, cb := range traveled {
+		if cb.Win {
+			comeBetPayout += cb.Payout
+		}
+		log.Printf("🎲 Come bet settled: point %d, win=%v, payout=%.2f", cb.Point, cb.Win, cb.Payout)
+	}
+
+	// Resolve the primary bet against the craps come-out/point state
+	// machine. This may mutate state.Phase/state.Point (pass/don't-pass
+	// establishing or clearing a point) or state.ComeBets (a fresh
+	// come/don't-come bet starting to travel).
+	resolution := resolveCrapsRoll(state, betType, betAmount, d1, d2, sum)
+	win := resolution.Win
+	payout := resolution.Payout
+	payoutMultiplier := resolution.Multiplier
+
+	if win {
 		// Apply house advantage feature flag if enabled
-		// This reduces win probability by 25% when the casino is losing too much money
-		if houseAdvantageEnabled {
+		// This reduces win probability by 25% when the casino is losing too much money.
+		// Gated out of fair mode: a committed seed chain is client-verifiable,
+		// so silently flipping a win to a loss would be provably dishonest.
+		// Also gated out whenever fairness state is unknown (fairnessUnknown)
+		// rather than definitively disabled, so a Redis blip can't re-enable
+		// the house edge against what might be an active fair session.
+		if houseAdvantageEnabled && !fairMode && !fairnessUnknown {
 			// 25% chance to convert a win into a loss (house advantage)
 			if rand.Float64() < 0.25 {
 				win = false
@@ -162,42 +250,45 @@ func (s *diceServer) Roll(ctx context.Context, req *pb.RollRequest) (*pb.RollRes
 		}
 	}
 
-	// Get username from request (if available in player_info)
-	username := "Anonymous"
-	if req.PlayerInfo != nil {
-		if u, ok := req.PlayerInfo["username"]; ok {
-			username = u
-		}
-	}
+	// Total payout for this roll is the primary bet's payout plus any
+	// traveling Come/Don't Come bets that just settled.
+	totalPayout := payout + comeBetPayout
+	win = win || comeBetPayout > 0
 
 	// Store game state in Redis
-	gameState := &GameState{
-		LastRoll:         time.Now(),
-		Dice1:            d1,
-		Dice2:            d2,
-		Sum:              sum,
-		Win:              win,
-		Payout:           payout,
-		BetAmount:        betAmount,
-		BetType:          betType,
-		PayoutMultiplier: payoutMultiplier,
-	}
-	if err := SaveGameState(ctx, username, gameState); err != nil {
+	state.LastRoll = time.Now()
+	state.Dice1 = d1
+	state.Dice2 = d2
+	state.Sum = sum
+	state.Win = win
+	state.Payout = totalPayout
+	state.BetAmount = betAmount
+	state.BetType = betType
+	state.PayoutMultiplier = payoutMultiplier
+	if err := s.store.Save(ctx, stateKey, state); err != nil {
 		log.Printf("Warning: Failed to save game state to Redis: %v", err)
 	}
 
-	// Record game result in scoring service for ALL games (wins and losses) to track total bets
+	// Record game result in scoring service for ALL games (wins and losses) to track total bets.
+	// A barred-12 push on don't-pass/don't-come returns the stake as Payout
+	// without being a win, so totalPayout alone isn't enough to call it "win".
 	result := "lose"
-	if win && payout > 0 {
+	if win && totalPayout > 0 {
 		result = "win"
 	}
 
+	traveledJSON, _ := json.Marshal(traveled)
+
 	// Prepare game data for scoring
 	gameData := map[string]interface{}{
-		"dice1":   d1,
-		"dice2":   d2,
-		"sum":     sum,
-		"betType": betType,
+		"dice1":         d1,
+		"dice2":         d2,
+		"sum":           sum,
+		"betType":       betType,
+		"phase":         state.Phase,
+		"point":         state.Point,
+		"comeBetPayout": comeBetPayout,
+		"comeBets":      json.RawMessage(traveledJSON),
 	}
 	gameDataJSON, _ := json.Marshal(gameData)
 
@@ -211,8 +302,8 @@ func (s *diceServer) Roll(ctx context.Context, req *pb.RollRequest) (*pb.RollRes
 		Game:      "dice",
 		Action:    "roll",
 		BetAmount: betAmount,
-		Payout:    payout,
-		Win:       win && payout > 0,
+		Payout:    totalPayout,
+		Win:       win,
 		Result:    result,
 		GameData:  string(gameDataJSON),
 		Metadata:  string(metadataJSON),
@@ -228,22 +319,120 @@ func (s *diceServer) Roll(ctx context.Context, req *pb.RollRequest) (*pb.RollRes
 		attribute.Int("game.dice2", d2),
 		attribute.Int("game.sum", sum),
 		attribute.Bool("game.win", win),
-		attribute.Float64("game.payout", payout),
+		attribute.Float64("game.payout", totalPayout),
+		attribute.Float64("game.come_bet_payout", comeBetPayout),
 		attribute.Float64("game.payout_multiplier", payoutMultiplier),
+		attribute.String("game.phase", state.Phase),
+		attribute.Int("game.point", state.Point),
+		attribute.String("game.resolution", resolution.Detail),
 	)
 
-	log.Printf("🎲 Dice Roll: %d+%d=%d, Bet: %s, Win: %v, Payout: %.2f", d1, d2, sum, betType, win, payout)
+	log.Printf("🎲 Dice Roll: %d+%d=%d, Bet: %s, Win: %v, Payout: %.2f (come bets: %.2f), Phase: %s, Point: %d (%s)",
+		d1, d2, sum, betType, win, totalPayout, comeBetPayout, state.Phase, state.Point, resolution.Detail)
 
 	return &pb.RollResponse{
-		Dice1:            int32(d1),
-		Dice2:            int32(d2),
-		Sum:              int32(sum),
-		Win:              win,
-		Payout:           payout,
-		BetAmount:        betAmount,
-		BetType:          betType,
-		PayoutMultiplier: payoutMultiplier,
-		Timestamp:        time.Now().UTC().Format(time.RFC3339),
+		Dice1:              int32(d1),
+		Dice2:              int32(d2),
+		Sum:                int32(sum),
+		Win:                win,
+		Payout:             totalPayout,
+		BetAmount:          betAmount,
+		BetType:            betType,
+		PayoutMultiplier:   payoutMultiplier,
+		Phase:              state.Phase,
+		Point:              int32(state.Point),
+		ResolutionDetail:   resolution.Detail,
+		ComeBetResolutions: string(traveledJSON),
+		Nonce:              rollNonce,
+		Timestamp:          time.Now().UTC().Format(time.RFC3339),
+	}, nil
+}
+
+// StartSession begins a new provably-fair commit-reveal chain for the
+// requesting player: a fresh server seed is generated, its SHA-256
+// commitment is returned immediately, and the seed itself stays secret
+// until RevealSeed is called. Every roll made while the session is active
+// is derived deterministically from this seed, the client's seed and a
+// per-roll nonce, so it can be replayed and verified once revealed.
+func (s *diceServer) StartSession(ctx context.Context, req *pb.StartSessionRequest) (*pb.StartSessionResponse, error) {
+	tracer := otel.Tracer("vegas-dice-service")
+	ctx, span := tracer.Start(ctx, "dice_start_session")
+	defer span.End()
+
+	username := "Anonymous"
+	if req.PlayerInfo != nil {
+		if u, ok := req.PlayerInfo["username"]; ok {
+			username = u
+		}
+	}
+	stateKey := resolveStateKey(username, req.SessionId)
+
+	clientSeed := req.ClientSeed
+	if clientSeed == "" {
+		return nil, fmt.Errorf("client_seed is required to start a fair session")
+	}
+
+	session, err := fairness.NewSession(clientSeed)
+	if err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("failed to start fairness session: %w", err)
+	}
+
+	if err := SaveFairnessSession(ctx, stateKey, session); err != nil {
+		log.Printf("Warning: Failed to save fairness session for %s: %v", stateKey, err)
+	}
+
+	span.SetAttributes(
+		attribute.String("game.client_seed", clientSeed),
+		attribute.String("game.commitment", session.Commitment),
+	)
+
+	return &pb.StartSessionResponse{
+		Commitment: session.Commitment,
+		ClientSeed: session.ClientSeed,
+	}, nil
+}
+
+// RevealSeed ends the requesting player's active fairness session and
+// returns the server seed committed to at StartSession time, along with the
+// final nonce reached, so the client can recompute and verify every roll
+// made during the session against the published commitment.
+func (s *diceServer) RevealSeed(ctx context.Context, req *pb.RevealSeedRequest) (*pb.RevealSeedResponse, error) {
+	tracer := otel.Tracer("vegas-dice-service")
+	ctx, span := tracer.Start(ctx, "dice_reveal_seed")
+	defer span.End()
+
+	username := "Anonymous"
+	if req.PlayerInfo != nil {
+		if u, ok := req.PlayerInfo["username"]; ok {
+			username = u
+		}
+	}
+	stateKey := resolveStateKey(username, req.SessionId)
+
+	session, err := GetFairnessSession(ctx, stateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load fairness session: %w", err)
+	}
+	if session == nil {
+		return nil, fmt.Errorf("no active fairness session for %s", stateKey)
+	}
+
+	session.Revealed = true
+	if err := SaveFairnessSession(ctx, stateKey, session); err != nil {
+		log.Printf("Warning: Failed to persist revealed fairness session for %s: %v", stateKey, err)
+	}
+
+	span.SetAttributes(
+		attribute.String("game.commitment", session.Commitment),
+		attribute.Int64("game.final_nonce", session.Nonce),
+	)
+
+	return &pb.RevealSeedResponse{
+		ServerSeed: session.ServerSeed,
+		ClientSeed: session.ClientSeed,
+		Commitment: session.Commitment,
+		FinalNonce: session.Nonce,
 	}, nil
 }
 
@@ -298,6 +487,10 @@ func generateDiceHTML() string {
 <body class="bg-dt-dark text-white p-4">
     <div id="dice-game-container" class="max-w-2xl mx-auto">
         <h1 class="text-3xl font-bold mb-4 text-center">🎲 Dice Game</h1>
+        <div id="table-state" class="text-center mb-4">
+            <span id="phase-label" class="font-bold uppercase tracking-wide">Come Out</span>
+            <span id="point-puck" class="inline-flex items-center justify-center w-8 h-8 ml-2 rounded-full bg-dt-gray" style="display: none;"></span>
+        </div>
         <div id="dice-result" class="text-center mb-4">
             <div class="flex justify-center gap-4 mb-4">
                 <div id="dice1" class="w-20 h-20 bg-dt-gray rounded-lg flex items-center justify-center text-4xl">?</div>
@@ -312,6 +505,7 @@ func generateDiceHTML() string {
                 <option value="dont_pass">Don't Pass</option>
                 <option value="field">Field</option>
                 <option value="come" id="come-option" style="display: none;">Come</option>
+                <option value="dont_come" id="dont-come-option" style="display: none;">Don't Come</option>
                 <option value="snake_eyes">Snake Eyes</option>
                 <option value="boxcars">Boxcars</option>
                 <option value="seven_out">Seven Out</option>
@@ -347,6 +541,18 @@ let grpcClient;
 const PASS_LINE_ENABLED = %s;
 const COME_BETS_ENABLED = %s;
 
+// Each browser gets its own craps table: the session id keys the server-side
+// GameState (come-out/point phase, traveling Come bets), so without this two
+// anonymous tabs would collide on the same table and resolve each other's bets.
+function getSessionId() {
+    let sessionId = localStorage.getItem('dice_session_id');
+    if (!sessionId) {
+        sessionId = crypto.randomUUID();
+        localStorage.setItem('dice_session_id', sessionId);
+    }
+    return sessionId;
+}
+
 async function initDiceGame() {
     console.log('Initializing dice game...');
     
@@ -358,22 +564,26 @@ async function initDiceGame() {
     if (COME_BETS_ENABLED) {
         const comeOption = document.getElementById('come-option');
         if (comeOption) comeOption.style.display = 'block';
+        const dontComeOption = document.getElementById('dont-come-option');
+        if (dontComeOption) dontComeOption.style.display = 'block';
     }
-    
+
     document.getElementById('roll-btn').addEventListener('click', async () => {
         const betAmount = parseFloat(document.getElementById('bet-amount').value);
         const betType = document.getElementById('bet-type').value;
-        
+
         try {
             const response = await callDiceService('Roll', {
                 bet_amount: betAmount,
-                bet_type: betType
+                bet_type: betType,
+                session_id: getSessionId()
             });
-            
+
             document.getElementById('dice1').textContent = response.dice1;
             document.getElementById('dice2').textContent = response.dice2;
             document.getElementById('sum').textContent = `+"`Sum: ${response.sum}`"+`;
-            
+            updateTableState(response.phase, response.point);
+
             if (response.win) {
                 document.getElementById('result').innerHTML = 
                     `+"`<div class=\"text-green-500 text-xl\">🎉 Win! Payout: $${response.payout.toFixed(2)}</div>`"+`;
@@ -389,6 +599,25 @@ async function initDiceGame() {
     });
 }
 
+function updateTableState(phase, point) {
+    const phaseLabel = document.getElementById('phase-label');
+    const puck = document.getElementById('point-puck');
+    if (!phaseLabel || !puck) return;
+
+    if (phase === 'point') {
+        phaseLabel.textContent = 'Point';
+        puck.textContent = point;
+        puck.style.display = 'inline-flex';
+        puck.classList.remove('bg-dt-gray');
+        puck.classList.add('bg-dt-cyan', 'text-black');
+    } else {
+        phaseLabel.textContent = 'Come Out';
+        puck.style.display = 'none';
+        puck.classList.remove('bg-dt-cyan', 'text-black');
+        puck.classList.add('bg-dt-gray');
+    }
+}
+
 async function callDiceService(method, data) {
     const response = await fetch(`+"`/api/dice/${method.toLowerCase()}`"+`, {
         method: 'POST',
@@ -463,6 +692,9 @@ func main() {
 	// Initialize Redis
 	InitializeRedis()
 
+	// Layer a bounded, TTL'd LRU in front of Redis for game state reads/writes
+	gameStateStore := NewGameStateStore()
+
 	// Initialize OpenTelemetry
 	serviceMetadata := map[string]string{
 		"version":      "2.1.0",
@@ -477,14 +709,6 @@ func main() {
 	tp, err := initTelemetry(serviceName, serviceMetadata)
 	if err != nil {
 		log.Printf("Failed to initialize OpenTelemetry: %v", err)
-	} else {
-		defer func() {
-			if tp != nil {
-				if err := tp.Shutdown(context.Background()); err != nil {
-					log.Printf("Error shutting down tracer provider: %v", err)
-				}
-			}
-		}()
 	}
 
 	// Start gRPC server
@@ -493,21 +717,13 @@ func main() {
 		log.Fatalf("Failed to listen: %v", err)
 	}
 
-	// Create gRPC server with OpenTelemetry interceptor for trace context propagation
-	// The interceptor extracts trace context from gRPC metadata
+	// Chain the trace-context-propagation interceptor with a recovery
+	// interceptor so a panic in a handler logs a span error and returns an
+	// Internal status instead of crashing the process.
 	s := grpc.NewServer(
-		grpc.UnaryInterceptor(func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
-			// Extract trace context from gRPC metadata
-			md, ok := metadata.FromIncomingContext(ctx)
-			if ok {
-				// Extract trace context using OpenTelemetry propagator
-				prop := otel.GetTextMapPropagator()
-				ctx = prop.Extract(ctx, metadataTextMapCarrier(md))
-			}
-			return handler(ctx, req)
-		}),
+		grpc.UnaryInterceptor(chainUnaryInterceptors(tracePropagationInterceptor, recoveryInterceptor)),
 	)
-	pb.RegisterDiceServiceServer(s, &diceServer{})
+	pb.RegisterDiceServiceServer(s, &diceServer{store: gameStateStore})
 	reflection.Register(s)
 
 	fmt.Printf("[%s] gRPC server listening on port %s\n", serviceName, grpcPort)
@@ -515,27 +731,94 @@ func main() {
 
 	// Start gRPC server in goroutine
 	go func() {
-		if err := s.Serve(lis); err != nil {
+		if err := s.Serve(lis); err != nil && err != grpc.ErrServerStopped {
 			log.Fatalf("Failed to serve gRPC: %v", err)
 		}
 	}()
 
-	// Keep HTTP server for backward compatibility
-	// Start HTTP server in a goroutine
-	go func() {
-		http.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
-			w.Header().Set("Content-Type", "application/json")
-			json.NewEncoder(w).Encode(map[string]interface{}{
-				"status":  "ok",
-				"service": serviceName,
-			})
+	// ready flips to false as soon as shutdown begins so /ready starts
+	// returning 503 and load balancers stop routing new traffic, while
+	// /health keeps reporting backend (Redis) health independently.
+	var ready atomic.Bool
+	ready.Store(true)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		status := "ok"
+		if !redisHealthy.Load() {
+			status = "degraded"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":       status,
+			"service":      serviceName,
+			"redisHealthy": redisHealthy.Load(),
 		})
+	})
+	mux.HandleFunc("/ready", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if !ready.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			json.NewEncoder(w).Encode(map[string]interface{}{"status": "shutting_down"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"status": "ready"})
+	})
+
+	// A real *http.Server (rather than the default mux global) so Shutdown
+	// below actually has something to drain.
+	httpServer := &http.Server{Addr: ":" + port, Handler: mux}
 
-		if err := http.ListenAndServe(":"+port, nil); err != nil {
+	go func() {
+		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			log.Printf("HTTP server error: %v", err)
 		}
 	}()
 
-	// Wait for termination
-	select {}
+	// Block until Kubernetes sends SIGTERM (or a local Ctrl-C), then drain.
+	ctx, stopSignals := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	<-ctx.Done()
+	stopSignals()
+
+	log.Printf("[%s] Shutdown signal received, draining in-flight requests...", serviceName)
+	ready.Store(false)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	httpStopped := make(chan struct{})
+	go func() {
+		if err := httpServer.Shutdown(shutdownCtx); err != nil {
+			log.Printf("Warning: HTTP server shutdown error: %v", err)
+		}
+		close(httpStopped)
+	}()
+
+	grpcStopped := make(chan struct{})
+	go func() {
+		s.GracefulStop()
+		close(grpcStopped)
+	}()
+
+	select {
+	case <-grpcStopped:
+	case <-shutdownCtx.Done():
+		log.Printf("Warning: gRPC graceful stop timed out, forcing Stop")
+		s.Stop()
+	}
+	<-httpStopped
+
+	if err := redisClient.Close(); err != nil {
+		log.Printf("Warning: error closing Redis client: %v", err)
+	}
+	if err := flagdClient.Close(); err != nil {
+		log.Printf("Warning: error closing flagd client: %v", err)
+	}
+	if tp != nil {
+		if err := tp.Shutdown(context.Background()); err != nil {
+			log.Printf("Error shutting down tracer provider: %v", err)
+		}
+	}
+
+	log.Printf("[%s] Shutdown complete", serviceName)
 }
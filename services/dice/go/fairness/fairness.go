@@ -0,0 +1,138 @@
+// Package fairness implements provably-fair dice rolls using a commit-reveal
+// scheme layered on an HMAC-SHA256 seed chain: the server commits to a seed
+// before any rolls happen, every roll is derived deterministically from that
+// seed plus the client's own seed and a roll counter, and the seed is only
+// revealed once the session is done so a client can recompute and verify
+// every historical roll.
+package fairness
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	mrand "math/rand"
+)
+
+// rejectionCeiling is the largest multiple of 6 that fits in a byte (6*42).
+// Bytes at or above it are discarded so that b%6 stays uniform over 0..5.
+const rejectionCeiling = 252
+
+// RollSource supplies raw entropy bytes used to derive dice values. It is
+// the pluggable boundary between the provably-fair HMAC chain and any
+// fallback RNG used for un-sessioned clients.
+type RollSource interface {
+	Bytes(n int) []byte
+}
+
+// MathRandSource is the legacy math/rand-backed source, kept as the fallback
+// for rolls that are not drawn from an active session's HMAC chain. Rolls
+// drawn from it cannot be verified against any commitment.
+type MathRandSource struct{}
+
+// Bytes returns n pseudo-random bytes from math/rand.
+func (MathRandSource) Bytes(n int) []byte {
+	b := make([]byte, n)
+	mrand.Read(b)
+	return b
+}
+
+// HMACSource derives bytes deterministically from a server seed, a client
+// seed and a nonce: HMAC-SHA256(serverSeed, clientSeed || ":" || nonce). A
+// single digest (32 bytes) covers every roll this game needs; if more bytes
+// are ever requested than one digest holds, additional digests are drawn by
+// appending a round counter to the message so the sequence stays
+// deterministic and reproducible by the client during verification.
+type HMACSource struct {
+	ServerSeed string
+	ClientSeed string
+	Nonce      int64
+
+	round int
+}
+
+// Bytes returns n bytes for this seed/nonce pair, starting at the first byte
+// of the next round's digest: bytes of an earlier call's digest that were
+// not returned are skipped, not carried over.
+func (h *HMACSource) Bytes(n int) []byte {
+	out := make([]byte, 0, n)
+	for len(out) < n {
+		mac := hmac.New(sha256.New, []byte(h.ServerSeed))
+		msg := fmt.Sprintf("%s:%d", h.ClientSeed, h.Nonce)
+		if h.round > 0 {
+			msg = fmt.Sprintf("%s:%d", msg, h.round)
+		}
+		mac.Write([]byte(msg))
+		out = append(out, mac.Sum(nil)...)
+		h.round++
+	}
+	return out[:n]
+}
+
+// RollDice draws count dice values in 1..6 from src using rejection sampling
+// to avoid modulo bias: bytes >= rejectionCeiling are discarded.
+func RollDice(src RollSource, count int) []int {
+	dice := make([]int, 0, count)
+	for len(dice) < count {
+		for _, b := range src.Bytes(count * 2) {
+			if b >= rejectionCeiling {
+				continue
+			}
+			dice = append(dice, int(b%6)+1)
+			if len(dice) == count {
+				break
+			}
+		}
+	}
+	return dice
+}
+
+// NewServerSeed generates a cryptographically random server seed.
+func NewServerSeed() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("fairness: generating server seed: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Commit returns the SHA-256 commitment for a server seed. It is safe to
+// publish to the client before the seed itself is revealed.
+func Commit(serverSeed string) string {
+	sum := sha256.Sum256([]byte(serverSeed))
+	return hex.EncodeToString(sum[:])
+}
+
+// Session is the server-side state of one player's provably-fair chain. It
+// is persisted in Redis under the dice:fairness: key prefix, keyed by the
+// player's session id (or username when no session id is sent), so rolls
+// survive across requests and the seed can be revealed later.
+type Session struct {
+	ServerSeed string `json:"server_seed"`
+	Commitment string `json:"commitment"`
+	ClientSeed string `json:"client_seed"`
+	Nonce      int64  `json:"nonce"`
+	Revealed   bool   `json:"revealed"`
+}
+
+// NewSession starts a new commit-reveal chain for clientSeed.
+func NewSession(clientSeed string) (*Session, error) {
+	serverSeed, err := NewServerSeed()
+	if err != nil {
+		return nil, err
+	}
+	return &Session{
+		ServerSeed: serverSeed,
+		Commitment: Commit(serverSeed),
+		ClientSeed: clientSeed,
+	}, nil
+}
+
+// Source returns the RollSource for the session's current nonce and
+// advances the nonce for the next roll.
+func (s *Session) Source() RollSource {
+	src := &HMACSource{ServerSeed: s.ServerSeed, ClientSeed: s.ClientSeed, Nonce: s.Nonce}
+	s.Nonce++
+	return src
+}
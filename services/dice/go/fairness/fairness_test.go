@@ -0,0 +1,75 @@
+package fairness
+
+import "testing"
+
+func TestHMACSourceDeterministic(t *testing.T) {
+	a := &HMACSource{ServerSeed: "server", ClientSeed: "client", Nonce: 1}
+	b := &HMACSource{ServerSeed: "server", ClientSeed: "client", Nonce: 1}
+
+	if string(a.Bytes(16)) != string(b.Bytes(16)) {
+		t.Fatal("expected identical seed/client seed/nonce to produce identical bytes")
+	}
+}
+
+func TestHMACSourceVariesByNonce(t *testing.T) {
+	a := &HMACSource{ServerSeed: "server", ClientSeed: "client", Nonce: 1}
+	b := &HMACSource{ServerSeed: "server", ClientSeed: "client", Nonce: 2}
+
+	if string(a.Bytes(16)) == string(b.Bytes(16)) {
+		t.Fatal("expected different nonces to produce different bytes")
+	}
+}
+
+func TestHMACSourceMultipleRounds(t *testing.T) {
+	src := &HMACSource{ServerSeed: "server", ClientSeed: "client", Nonce: 1}
+	out := src.Bytes(64)
+	if len(out) != 64 {
+		t.Fatalf("got %d bytes, want 64", len(out))
+	}
+}
+
+func TestRollDiceRange(t *testing.T) {
+	src := &HMACSource{ServerSeed: "server", ClientSeed: "client", Nonce: 1}
+	for i := 0; i < 100; i++ {
+		for _, d := range RollDice(src, 2) {
+			if d < 1 || d > 6 {
+				t.Fatalf("got die value %d, want 1..6", d)
+			}
+		}
+	}
+}
+
+func TestCommitMatchesSeed(t *testing.T) {
+	seed, err := NewServerSeed()
+	if err != nil {
+		t.Fatalf("NewServerSeed: %v", err)
+	}
+	commitment := Commit(seed)
+	if commitment != Commit(seed) {
+		t.Fatal("expected Commit to be deterministic for the same seed")
+	}
+	if commitment == seed {
+		t.Fatal("expected commitment to differ from the raw seed")
+	}
+}
+
+func TestSessionSourceAdvancesNonce(t *testing.T) {
+	session, err := NewSession("client-seed")
+	if err != nil {
+		t.Fatalf("NewSession: %v", err)
+	}
+
+	if session.Nonce != 0 {
+		t.Fatalf("got initial nonce %d, want 0", session.Nonce)
+	}
+
+	first := session.Source()
+	if session.Nonce != 1 {
+		t.Fatalf("got nonce %d after first Source(), want 1", session.Nonce)
+	}
+
+	second := session.Source()
+	if string(first.Bytes(8)) == string(second.Bytes(8)) {
+		t.Fatal("expected consecutive rolls to use different nonces")
+	}
+}
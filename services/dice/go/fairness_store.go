@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"vegas-dice-service/fairness"
+)
+
+const (
+	fairnessKeyPrefix = "dice:fairness:"
+	fairnessTTL       = 24 * 3600 // 24 hours, long enough to span a session's reveal
+)
+
+// fairnessKey builds the Redis key for a fairness session, hash-tagged the
+// same way as gameStateKey so both land on the same cluster slot. stateKey
+// is the same SessionId-preferring key GameState is stored under (see
+// resolveStateKey), not a raw username, so two callers sharing a username
+// but sending different session ids never collide on one seed chain.
+func fairnessKey(stateKey string) string {
+	return fairnessKeyPrefix + "{" + stateKey + "}"
+}
+
+// GetFairnessSession retrieves a player's provably-fair session from Redis.
+func GetFairnessSession(ctx context.Context, stateKey string) (*fairness.Session, error) {
+	if !redisHealthy.Load() {
+		return nil, fmt.Errorf("Redis client not available")
+	}
+
+	val, err := redisClient.Get(ctx, fairnessKey(stateKey)).Result()
+	if err == redis.Nil {
+		return nil, nil // No session found
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting fairness session from Redis: %w", err)
+	}
+
+	var session fairness.Session
+	if err := json.Unmarshal([]byte(val), &session); err != nil {
+		return nil, fmt.Errorf("error parsing fairness session: %w", err)
+	}
+
+	return &session, nil
+}
+
+// SaveFairnessSession persists a player's provably-fair session to Redis.
+func SaveFairnessSession(ctx context.Context, stateKey string, session *fairness.Session) error {
+	if !redisHealthy.Load() {
+		return nil // Silently fail if Redis is not available
+	}
+
+	sessionJSON, err := json.Marshal(session)
+	if err != nil {
+		return fmt.Errorf("error marshaling fairness session: %w", err)
+	}
+
+	if err := redisClient.Set(ctx, fairnessKey(stateKey), sessionJSON, fairnessTTL*time.Second).Err(); err != nil {
+		return fmt.Errorf("error saving fairness session to Redis: %w", err)
+	}
+
+	return nil
+}
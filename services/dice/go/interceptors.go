@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"runtime/debug"
+
+	"go.opentelemetry.io/otel"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	grpcstatus "google.golang.org/grpc/status"
+)
+
+// tracePropagationInterceptor extracts an incoming trace context from gRPC
+// metadata so spans started inside a handler join the caller's trace.
+func tracePropagationInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if ok {
+		prop := otel.GetTextMapPropagator()
+		ctx = prop.Extract(ctx, metadataTextMapCarrier(md))
+	}
+	return handler(ctx, req)
+}
+
+// recoveryInterceptor turns a panic in a handler into a logged span error
+// and an Internal gRPC status, instead of taking down the whole process.
+func recoveryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("[Dice] panic in %s: %v\n%s", info.FullMethod, r, debug.Stack())
+
+			// The handler's own span has already ended by the time its panic
+			// reaches here, and the span in ctx is only the caller's remote
+			// context, so record the panic on a span of its own.
+			_, span := otel.Tracer("vegas-dice-service").Start(ctx, "grpc_panic_recovered")
+			span.RecordError(fmt.Errorf("panic: %v", r))
+			span.SetStatus(otelcodes.Error, "panic recovered")
+			span.End()
+
+			err = grpcstatus.Errorf(codes.Internal, "internal error handling %s", info.FullMethod)
+		}
+	}()
+	return handler(ctx, req)
+}
+
+// chainUnaryInterceptors composes interceptors so the first one runs
+// outermost, matching the order they're listed in main.
+func chainUnaryInterceptors(interceptors ...grpc.UnaryServerInterceptor) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		chained := handler
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor := interceptors[i]
+			next := chained
+			chained = func(ctx context.Context, req interface{}) (interface{}, error) {
+				return interceptor(ctx, req, info, next)
+			}
+		}
+		return chained(ctx, req)
+	}
+}
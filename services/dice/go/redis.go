@@ -2,46 +2,164 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
 	"fmt"
 	"log"
 	"os"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 )
 
-var redisClient *redis.Client
+var redisClient redis.UniversalClient
 
-// InitializeRedis initializes the Redis client
+// redisHealthy reflects the result of the most recent background health
+// check. It backs the /health HTTP endpoint and the gRPC Health RPC so
+// orchestrators can see backend degradation without themselves round
+// tripping to Redis.
+var redisHealthy atomic.Bool
+
+// InitializeRedis builds a redis.UniversalClient for the configured
+// REDIS_MODE ("single", "sentinel" or "cluster") and starts a background
+// health check that keeps redisHealthy up to date.
 func InitializeRedis() {
-	redisHost := getEnvOrDefault("REDIS_HOST", "localhost")
-	redisPort := getEnvOrDefault("REDIS_PORT", "6379")
-	redisPassword := getEnvOrDefault("REDIS_PASSWORD", "")
+	mode := getEnvOrDefault("REDIS_MODE", "single")
+	addrs := redisAddrs()
+
+	opts := &redis.UniversalOptions{
+		Addrs:      addrs,
+		Password:   getEnvOrDefault("REDIS_PASSWORD", ""),
+		MasterName: getEnvOrDefault("REDIS_MASTER_NAME", ""),
+	}
+
+	if tlsConfig, err := redisTLSConfig(); err != nil {
+		log.Printf("Warning: Failed to build Redis TLS config: %v. Connecting without TLS.", err)
+	} else if tlsConfig != nil {
+		opts.TLSConfig = tlsConfig
+	}
 
-	// Construct address from host and port
-	redisAddr := fmt.Sprintf("%s:%s", redisHost, redisPort)
+	switch mode {
+	case "cluster":
+		// go-redis picks a cluster client when RouteRandomly/cluster-shaped
+		// options are given a multi-address Addrs slice and no MasterName;
+		// setting it explicitly here keeps the mode switch self-documenting.
+		opts.MasterName = ""
+		if len(addrs) <= 1 {
+			log.Printf("Warning: REDIS_MODE=cluster but only one address is configured (%v); go-redis will connect as a single node instead of a cluster client", addrs)
+		}
+	case "sentinel":
+		if opts.MasterName == "" {
+			log.Printf("Warning: REDIS_MODE=sentinel but REDIS_MASTER_NAME is not set")
+		}
+	case "single":
+		// Single-node: UniversalClient falls back to a plain *redis.Client
+		// as long as only one address and no MasterName are set.
+	default:
+		log.Printf("Warning: unknown REDIS_MODE %q, falling back to single", mode)
+	}
 
-	redisClient = redis.NewClient(&redis.Options{
-		Addr:     redisAddr,
-		Password: redisPassword,
-		DB:       0,
-	})
+	redisClient = redis.NewUniversalClient(opts)
 
-	// Test connection
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	_, err := redisClient.Ping(ctx).Result()
-	if err != nil {
-		log.Printf("Warning: Failed to connect to Redis: %v. Game state will not be persisted.", err)
-		redisClient = nil
+	if _, err := redisClient.Ping(ctx).Result(); err != nil {
+		log.Printf("Warning: Failed to connect to Redis (mode=%s, addrs=%v): %v. Game state will not be persisted.", mode, addrs, err)
+		redisHealthy.Store(false)
 	} else {
-		log.Printf("✅ Connected to Redis at %s", redisAddr)
+		log.Printf("✅ Connected to Redis (mode=%s, addrs=%v)", mode, addrs)
+		redisHealthy.Store(true)
+	}
+
+	go runRedisHealthCheck()
+}
+
+// redisAddrs parses the comma-separated REDIS_ADDRS, falling back to the
+// legacy REDIS_HOST/REDIS_PORT pair for single-node deployments that
+// haven't migrated yet.
+func redisAddrs() []string {
+	if raw := os.Getenv("REDIS_ADDRS"); raw != "" {
+		var addrs []string
+		for _, addr := range strings.Split(raw, ",") {
+			if addr = strings.TrimSpace(addr); addr != "" {
+				addrs = append(addrs, addr)
+			}
+		}
+		if len(addrs) > 0 {
+			return addrs
+		}
 	}
+
+	redisHost := getEnvOrDefault("REDIS_HOST", "localhost")
+	redisPort := getEnvOrDefault("REDIS_PORT", "6379")
+	return []string{fmt.Sprintf("%s:%s", redisHost, redisPort)}
 }
 
-// GameState represents the state of a dice game
+// redisTLSConfig builds a *tls.Config from REDIS_TLS_ENABLED and the
+// accompanying cert/key/ca paths. It returns a nil config when TLS is
+// disabled so callers can tell "not configured" apart from "configured".
+func redisTLSConfig() (*tls.Config, error) {
+	if getEnvOrDefault("REDIS_TLS_ENABLED", "false") != "true" {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{}
+
+	certPath := os.Getenv("REDIS_TLS_CERT_PATH")
+	keyPath := os.Getenv("REDIS_TLS_KEY_PATH")
+	if certPath != "" && keyPath != "" {
+		cert, err := tls.LoadX509KeyPair(certPath, keyPath)
+		if err != nil {
+			return nil, fmt.Errorf("loading Redis TLS client cert: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	if caPath := os.Getenv("REDIS_TLS_CA_PATH"); caPath != "" {
+		caCert, err := os.ReadFile(caPath)
+		if err != nil {
+			return nil, fmt.Errorf("reading Redis TLS CA: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("no certificates parsed from Redis TLS CA %s", caPath)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	return tlsConfig, nil
+}
+
+// runRedisHealthCheck periodically pings Redis and updates redisHealthy so
+// degradation is visible without waiting on a user-facing request to fail.
+func runRedisHealthCheck() {
+	ticker := time.NewTicker(10 * time.Second)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		_, err := redisClient.Ping(ctx).Result()
+		cancel()
+
+		healthy := err == nil
+		if healthy != redisHealthy.Swap(healthy) {
+			if healthy {
+				log.Printf("✅ Redis connection recovered")
+			} else {
+				log.Printf("Warning: Redis health check failed: %v", err)
+			}
+		}
+	}
+}
+
+// GameState represents the state of a dice game, including the craps
+// come-out/point state machine: Phase and Point track the main pass-line
+// cycle, and ComeBets tracks any Come/Don't Come bets still traveling to
+// their own point.
 type GameState struct {
 	LastRoll         time.Time `json:"last_roll"`
 	Dice1            int       `json:"dice1"`
@@ -52,6 +170,10 @@ type GameState struct {
 	BetAmount        float64   `json:"bet_amount"`
 	BetType          string    `json:"bet_type"`
 	PayoutMultiplier float64   `json:"payout_multiplier"`
+
+	Phase    string    `json:"phase"`
+	Point    int       `json:"point"`
+	ComeBets []ComeBet `json:"come_bets,omitempty"`
 }
 
 const (
@@ -59,14 +181,21 @@ const (
 	gameStateTTL       = 3600 // 1 hour
 )
 
+// gameStateKey builds the Redis key for a user's game state. The username
+// is wrapped in a hash tag ({...}) so every key belonging to one user - this
+// one plus e.g. dice:fairness:{user} - lands on the same cluster slot,
+// which multi-key operations like MGET or pipelines require.
+func gameStateKey(username string) string {
+	return gameStateKeyPrefix + "{" + username + "}"
+}
+
 // GetGameState retrieves game state from Redis
 func GetGameState(ctx context.Context, username string) (*GameState, error) {
-	if redisClient == nil {
-		return nil, fmt.Errorf("Redis client not initialized")
+	if !redisHealthy.Load() {
+		return nil, fmt.Errorf("Redis client not available")
 	}
 
-	key := gameStateKeyPrefix + username
-	val, err := redisClient.Get(ctx, key).Result()
+	val, err := redisClient.Get(ctx, gameStateKey(username)).Result()
 	if err == redis.Nil {
 		return nil, nil // No state found
 	}
@@ -84,17 +213,16 @@ func GetGameState(ctx context.Context, username string) (*GameState, error) {
 
 // SaveGameState saves game state to Redis
 func SaveGameState(ctx context.Context, username string, state *GameState) error {
-	if redisClient == nil {
+	if !redisHealthy.Load() {
 		return nil // Silently fail if Redis is not available
 	}
 
-	key := gameStateKeyPrefix + username
 	stateJSON, err := json.Marshal(state)
 	if err != nil {
 		return fmt.Errorf("error marshaling game state: %w", err)
 	}
 
-	err = redisClient.Set(ctx, key, stateJSON, gameStateTTL*time.Second).Err()
+	err = redisClient.Set(ctx, gameStateKey(username), stateJSON, gameStateTTL*time.Second).Err()
 	if err != nil {
 		return fmt.Errorf("error saving game state to Redis: %w", err)
 	}
@@ -104,12 +232,11 @@ func SaveGameState(ctx context.Context, username string, state *GameState) error
 
 // DeleteGameState deletes game state from Redis
 func DeleteGameState(ctx context.Context, username string) error {
-	if redisClient == nil {
+	if !redisHealthy.Load() {
 		return nil // Silently fail if Redis is not available
 	}
 
-	key := gameStateKeyPrefix + username
-	err := redisClient.Del(ctx, key).Err()
+	err := redisClient.Del(ctx, gameStateKey(username)).Err()
 	if err != nil {
 		return fmt.Errorf("error deleting game state from Redis: %w", err)
 	}